@@ -0,0 +1,149 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKey representa uma chave de aplicação emitida para um cliente,
+// incluindo os limites de taxa aplicados pelo rateLimitMiddleware.
+type APIKey struct {
+	Key               string
+	Label             string
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+// KeyStore abstrai onde as chaves de aplicação são cadastradas. A
+// implementação padrão lê de uma variável de ambiente; um store baseado em
+// banco pode ser plugado sem alterar o middleware.
+type KeyStore interface {
+	Lookup(key string) (*APIKey, bool)
+}
+
+var keyStore KeyStore
+
+// contextKeyAPIKey é a chave usada para guardar a APIKey autenticada no
+// gin.Context, lida pelo middleware de rate limit e pelos handlers de uso.
+const contextKeyAPIKey = "apiKey"
+
+func initAuth() {
+	keyStore = newEnvKeyStore()
+}
+
+// authMiddleware exige um `Authorization: Bearer <app-key>` válido e expõe a
+// APIKey correspondente no contexto da requisição para os middlewares
+// seguintes (rate limit, accounting).
+func authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if token == "" || token == header {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization Bearer <app-key> ausente"})
+			return
+		}
+
+		apiKey, ok := keyStore.Lookup(token)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "chave de API inválida"})
+			return
+		}
+
+		c.Set(contextKeyAPIKey, apiKey)
+		c.Next()
+	}
+}
+
+// adminAuthMiddleware protege as rotas /api/admin/* com uma chave separada,
+// configurada em ADMIN_API_KEY, para não misturar acesso de admin com as
+// app-keys de clientes.
+func adminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminKey := os.Getenv("ADMIN_API_KEY")
+
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+
+		if adminKey == "" || token == header || token != adminKey {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "acesso de admin não autorizado"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// currentAPIKey recupera a APIKey autenticada pelo authMiddleware.
+func currentAPIKey(c *gin.Context) *APIKey {
+	value, ok := c.Get(contextKeyAPIKey)
+	if !ok {
+		return nil
+	}
+	return value.(*APIKey)
+}
+
+// envKeyStore carrega as chaves válidas de APP_KEYS, no formato
+// "chave:label:rpm:tpm,chave2:label2:rpm2:tpm2". Pensado para um número
+// pequeno de clientes; um volume maior deve migrar para um KeyStore com
+// banco de dados.
+type envKeyStore struct {
+	keys map[string]*APIKey
+}
+
+func newEnvKeyStore() *envKeyStore {
+	store := &envKeyStore{keys: make(map[string]*APIKey)}
+
+	raw := os.Getenv("APP_KEYS")
+	if raw == "" {
+		return store
+	}
+
+	for _, entry := range strings.Split(raw, ",") {
+		fields := strings.Split(strings.TrimSpace(entry), ":")
+		if len(fields) < 1 || fields[0] == "" {
+			continue
+		}
+
+		apiKey := &APIKey{
+			Key:               fields[0],
+			Label:             fields[0],
+			RequestsPerMinute: 60,
+			TokensPerMinute:   100000,
+		}
+		if len(fields) > 1 && fields[1] != "" {
+			apiKey.Label = fields[1]
+		}
+		if len(fields) > 2 {
+			apiKey.RequestsPerMinute = atoiOrDefault(fields[2], apiKey.RequestsPerMinute)
+		}
+		if len(fields) > 3 {
+			apiKey.TokensPerMinute = atoiOrDefault(fields[3], apiKey.TokensPerMinute)
+		}
+
+		store.keys[apiKey.Key] = apiKey
+	}
+
+	return store
+}
+
+func (s *envKeyStore) Lookup(key string) (*APIKey, bool) {
+	apiKey, ok := s.keys[key]
+	return apiKey, ok
+}
+
+func atoiOrDefault(value string, fallback int) int {
+	n := 0
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return fallback
+		}
+		n = n*10 + int(r-'0')
+	}
+	if n == 0 {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// geminiBackend implementa Backend usando a API REST do Gemini.
+type geminiBackend struct {
+	apiKey string
+}
+
+func newGeminiBackend(apiKey string) *geminiBackend {
+	return &geminiBackend{apiKey: apiKey}
+}
+
+func (b *geminiBackend) Name() string {
+	return "gemini"
+}
+
+func (b *geminiBackend) Generate(ctx context.Context, prompt string, opts BackendOptions) (GenerateResult, error) {
+	model := opts.Model
+	if model == "" {
+		model = "gemini-pro"
+	}
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", model, b.apiKey)
+
+	requestBody := GeminiRequest{
+		Contents: []Content{
+			{
+				Parts: []Part{
+					{Text: prompt},
+				},
+			},
+		},
+		SafetySettings: defaultSafetySettings,
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return GenerateResult{}, fmt.Errorf("erro na API: %s - %s", resp.Status, string(body))
+	}
+
+	var geminiResp GeminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
+		return GenerateResult{}, err
+	}
+
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return GenerateResult{}, fmt.Errorf("resposta vazia do Gemini")
+	}
+
+	return GenerateResult{
+		Text:             geminiResp.Candidates[0].Content.Parts[0].Text,
+		PromptTokens:     geminiResp.UsageMetadata.PromptTokenCount,
+		CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
+	}, nil
+}
+
+// GenerateStream ainda não suporta streaming real para o Gemini; entrega a
+// resposta completa como um único token. O endpoint de streaming dedicado
+// usa streamGenerateContent diretamente.
+func (b *geminiBackend) GenerateStream(ctx context.Context, prompt string, opts BackendOptions, onToken func(string)) error {
+	result, err := b.Generate(ctx, prompt, opts)
+	if err != nil {
+		return err
+	}
+	onToken(result.Text)
+	return nil
+}
+
+// GenerateWithContents chama o Gemini com um histórico de Contents já
+// montado (papéis user/model alternados), usado pelas sessões de conversa
+// para que o modelo enxergue os turnos anteriores em vez de um prompt único.
+func (b *geminiBackend) GenerateWithContents(ctx context.Context, contents []Content, opts BackendOptions) (GenerateResult, error) {
+	model := opts.Model
+	if model == "" {
+		model = "gemini-pro"
+	}
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", model, b.apiKey)
+
+	jsonData, err := json.Marshal(GeminiRequest{Contents: contents, SafetySettings: defaultSafetySettings})
+	if err != nil {
+		return GenerateResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return GenerateResult{}, fmt.Errorf("erro na API: %s - %s", resp.Status, string(body))
+	}
+
+	var geminiResp GeminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
+		return GenerateResult{}, err
+	}
+
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return GenerateResult{}, fmt.Errorf("resposta vazia do Gemini")
+	}
+
+	return GenerateResult{
+		Text:             geminiResp.Candidates[0].Content.Parts[0].Text,
+		PromptTokens:     geminiResp.UsageMetadata.PromptTokenCount,
+		CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
+	}, nil
+}
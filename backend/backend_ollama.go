@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ollamaBackend implementa Backend contra um servidor Ollama local, usado
+// para rodar modelos abertos na mesma máquina/rede do backend.
+type ollamaBackend struct {
+	baseURL string
+}
+
+func newOllamaBackend(baseURL string) *ollamaBackend {
+	return &ollamaBackend{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (b *ollamaBackend) Name() string {
+	return "ollama"
+}
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaGenerateResponse struct {
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+}
+
+func (b *ollamaBackend) Generate(ctx context.Context, prompt string, opts BackendOptions) (GenerateResult, error) {
+	model := opts.Model
+	if model == "" {
+		model = "llama3"
+	}
+
+	jsonData, err := json.Marshal(ollamaGenerateRequest{Model: model, Prompt: prompt, Stream: false})
+	if err != nil {
+		return GenerateResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return GenerateResult{}, fmt.Errorf("erro na API do Ollama: %s", resp.Status)
+	}
+
+	var ollamaResp ollamaGenerateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return GenerateResult{}, err
+	}
+
+	return GenerateResult{
+		Text:             ollamaResp.Response,
+		PromptTokens:     ollamaResp.PromptEvalCount,
+		CompletionTokens: ollamaResp.EvalCount,
+	}, nil
+}
+
+// GenerateStream consome o stream de objetos JSON do Ollama (um por linha) e
+// repassa cada fragmento de texto assim que chega.
+func (b *ollamaBackend) GenerateStream(ctx context.Context, prompt string, opts BackendOptions, onToken func(string)) error {
+	model := opts.Model
+	if model == "" {
+		model = "llama3"
+	}
+
+	jsonData, err := json.Marshal(ollamaGenerateRequest{Model: model, Prompt: prompt, Stream: true})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("erro na API do Ollama: %s", resp.Status)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for decoder.More() {
+		var chunk ollamaGenerateResponse
+		if err := decoder.Decode(&chunk); err != nil {
+			return err
+		}
+		if chunk.Response != "" {
+			onToken(chunk.Response)
+		}
+		if chunk.Done {
+			break
+		}
+	}
+
+	return nil
+}
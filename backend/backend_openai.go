@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// openaiBackend implementa Backend contra qualquer API compatível com o
+// formato de chat completions da OpenAI (OpenAI, Azure OpenAI, vLLM, etc).
+type openaiBackend struct {
+	apiKey  string
+	baseURL string
+}
+
+func newOpenAIBackend(apiKey string) *openaiBackend {
+	baseURL := os.Getenv("OPENAI_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	return &openaiBackend{apiKey: apiKey, baseURL: baseURL}
+}
+
+func (b *openaiBackend) Name() string {
+	return "openai"
+}
+
+type openAIChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []openAIChatMessage `json:"messages"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (b *openaiBackend) Generate(ctx context.Context, prompt string, opts BackendOptions) (GenerateResult, error) {
+	model := opts.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+
+	requestBody := openAIChatRequest{
+		Model: model,
+		Messages: []openAIChatMessage{
+			{Role: "user", Content: prompt},
+		},
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return GenerateResult{}, fmt.Errorf("erro na API: %s - %s", resp.Status, string(body))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return GenerateResult{}, err
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return GenerateResult{}, fmt.Errorf("resposta vazia da API compatível com OpenAI")
+	}
+
+	return GenerateResult{
+		Text:             chatResp.Choices[0].Message.Content,
+		PromptTokens:     chatResp.Usage.PromptTokens,
+		CompletionTokens: chatResp.Usage.CompletionTokens,
+	}, nil
+}
+
+// GenerateStream entrega a resposta completa como um único token; streaming
+// via Server-Sent Events do provedor fica para uma próxima iteração.
+func (b *openaiBackend) GenerateStream(ctx context.Context, prompt string, opts BackendOptions, onToken func(string)) error {
+	result, err := b.Generate(ctx, prompt, opts)
+	if err != nil {
+		return err
+	}
+	onToken(result.Text)
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// BackendOptions carrega parâmetros opcionais repassados ao backend escolhido.
+type BackendOptions struct {
+	Model       string
+	Temperature float64
+}
+
+// GenerateResult é o retorno de Backend.Generate: a resposta completa e uma
+// estimativa do consumo de tokens, usada pelo accounting de uso.
+type GenerateResult struct {
+	Text             string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Backend abstrai a origem das respostas (Gemini, OpenAI-compatible, Ollama).
+// Isso permite trocar o provedor de LLM por configuração sem tocar no
+// handler HTTP. Um backend gRPC para servir modelos locais via worker (no
+// espírito do backend gRPC do LocalAI) foi cogitado mas removido antes de
+// chegar a esta árvore, por falta dos stubs gerados do proto — ainda não
+// está implementado.
+type Backend interface {
+	// Name identifica o backend em respostas de API e logs.
+	Name() string
+	// Generate retorna a resposta completa para o prompt informado.
+	Generate(ctx context.Context, prompt string, opts BackendOptions) (GenerateResult, error)
+	// GenerateStream entrega a resposta em pedaços através de onToken, na
+	// ordem em que chegam do provedor. Deve respeitar o cancelamento de ctx.
+	GenerateStream(ctx context.Context, prompt string, opts BackendOptions, onToken func(string)) error
+}
+
+// estimateTokens aproxima a contagem de tokens de um texto pela regra prática
+// de ~4 caracteres por token, usada pelo endpoint de streaming, que não recebe
+// o uso real reportado pelo Gemini.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// backends mantém os backends disponíveis indexados pelo nome usado em
+// QuestionRequest.Backend e na variável de ambiente DEFAULT_BACKEND.
+var backends map[string]Backend
+
+// defaultBackendName é usado quando QuestionRequest.Backend vem vazio.
+var defaultBackendName string
+
+// initBackends monta os backends suportados a partir da configuração do
+// ambiente. Backends que não tiverem a configuração necessária (ex.: chave de
+// API) simplesmente não são registrados.
+func initBackends() {
+	backends = make(map[string]Backend)
+
+	if geminiAPIKey != "" {
+		backends["gemini"] = newGeminiBackend(geminiAPIKey)
+	}
+
+	if openaiKey := os.Getenv("OPENAI_API_KEY"); openaiKey != "" {
+		backends["openai"] = newOpenAIBackend(openaiKey)
+	}
+
+	if ollamaURL := os.Getenv("OLLAMA_URL"); ollamaURL != "" {
+		backends["ollama"] = newOllamaBackend(ollamaURL)
+	}
+
+	defaultBackendName = os.Getenv("DEFAULT_BACKEND")
+	if defaultBackendName == "" || backends[defaultBackendName] == nil {
+		if _, ok := backends["gemini"]; ok {
+			defaultBackendName = "gemini"
+		}
+		for name := range backends {
+			if defaultBackendName == "" {
+				defaultBackendName = name
+			}
+		}
+	}
+}
+
+// resolveBackend escolhe o backend pelo nome informado na requisição, caindo
+// para o backend padrão quando vazio.
+func resolveBackend(name string) (Backend, error) {
+	if name == "" {
+		name = defaultBackendName
+	}
+
+	backend, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("backend desconhecido: %q", name)
+	}
+
+	return backend, nil
+}
+
+// listBackends retorna, para o endpoint /api/backends, o nome de cada backend
+// disponível e qual deles está selecionado por padrão.
+func listBackends() (available []string, current string) {
+	for name := range backends {
+		available = append(available, name)
+	}
+	return available, defaultBackendName
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// Document é um material de estudo enviado pelo usuário (PDF ou Markdown),
+// dividido em pedaços menores para busca por similaridade.
+type Document struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Chunks []string `json:"-"`
+}
+
+// Chunk é um pedaço de um Document já embutido em um vetor, pronto para ser
+// comparado com a pergunta do usuário.
+type Chunk struct {
+	ID           string    `json:"id"`
+	DocumentID   string    `json:"document_id"`
+	DocumentName string    `json:"document_name"`
+	Text         string    `json:"text"`
+	Embedding    []float32 `json:"-"`
+}
+
+// VectorStore abstrai onde os chunks embutidos são guardados e como a busca
+// por similaridade é feita, permitindo trocar a implementação em memória por
+// um banco com suporte a vetores (ex.: pgvector) sem alterar o pipeline de
+// ingestão ou o RAG em askQuestion.
+type VectorStore interface {
+	Add(chunks []Chunk) error
+	// Search retorna os k chunks mais próximos do vetor informado.
+	Search(embedding []float32, k int) ([]Chunk, error)
+}
+
+var vectorStore VectorStore
+
+// chunkSize é o tamanho aproximado, em caracteres, de cada pedaço gerado na
+// ingestão. Pequeno o bastante para caber com folga no contexto do Gemini.
+const chunkSize = 1200
+
+func initVectorStore() {
+	if dsn := os.Getenv("PGVECTOR_DATABASE_URL"); dsn != "" {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			log.Printf("Erro ao conectar ao pgvector, usando busca em memória: %v", err)
+		} else {
+			vectorStore = newPgvectorStore(db)
+			return
+		}
+	}
+
+	vectorStore = newMemoryVectorStore()
+}
+
+// chunkText divide o texto de um documento em pedaços de tamanho aproximado
+// chunkSize, quebrando apenas em limites de parágrafo para não cortar uma
+// ideia ao meio. Um parágrafo maior que chunkSize não é dividido e vira um
+// chunk inteiro, maior que chunkSize.
+func chunkText(text string) []string {
+	paragraphs := strings.Split(text, "\n\n")
+
+	var chunks []string
+	var current strings.Builder
+
+	for _, paragraph := range paragraphs {
+		if current.Len()+len(paragraph) > chunkSize && current.Len() > 0 {
+			chunks = append(chunks, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+		current.WriteString(paragraph)
+		current.WriteString("\n\n")
+	}
+
+	if strings.TrimSpace(current.String()) != "" {
+		chunks = append(chunks, strings.TrimSpace(current.String()))
+	}
+
+	return chunks
+}
+
+func newChunkID(documentID string, index int) string {
+	return fmt.Sprintf("%s-%d", documentID, index)
+}
+
+// retrieveChunks embute a pergunta e busca os chunks mais relevantes no
+// VectorStore configurado, usados para montar o contexto do RAG.
+func retrieveChunks(ctx context.Context, question string) ([]Chunk, error) {
+	embedding, err := embedText(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+
+	return vectorStore.Search(embedding, ragTopK)
+}
+
+// createRAGPrompt estende createPrompt com uma seção "Contexto" contendo os
+// chunks recuperados do material de estudo, cada um citando o documento de
+// origem para que o modelo referencie as fontes na resposta.
+func createRAGPrompt(question, topic string, chunks []Chunk) string {
+	if len(chunks) == 0 {
+		return createPrompt(question, topic)
+	}
+
+	prompt := createPrompt(question, topic)
+	marker := "\n\nResposta:"
+
+	var context strings.Builder
+	context.WriteString("\n\nContexto (trechos do material de estudo, use-os para responder e cite a fonte entre colchetes):\n")
+	for _, chunk := range chunks {
+		context.WriteString(fmt.Sprintf("[%s] %s\n\n", chunk.DocumentName, chunk.Text))
+	}
+
+	return strings.TrimSuffix(prompt, marker) + context.String() + marker
+}
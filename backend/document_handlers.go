@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ledongthuc/pdf"
+)
+
+// ragTopK é o número de chunks recuperados para compor o contexto de uma
+// pergunta com use_rag=true.
+const ragTopK = 4
+
+// uploadDocument atende POST /api/documents: recebe um PDF ou Markdown,
+// divide o conteúdo em chunks, gera os embeddings via Gemini e guarda tudo
+// no VectorStore configurado.
+func uploadDocument(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo não informado"})
+		return
+	}
+	defer file.Close()
+
+	raw, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao ler arquivo"})
+		return
+	}
+
+	text, err := extractText(header.Filename, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	document := &Document{ID: newID(), Name: header.Filename, Chunks: chunkText(text)}
+
+	chunks := make([]Chunk, 0, len(document.Chunks))
+	for i, chunkTextValue := range document.Chunks {
+		embedding, err := embedText(c.Request.Context(), chunkTextValue)
+		if err != nil {
+			log.Printf("Erro ao gerar embedding do chunk %d de %s: %v", i, document.Name, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar documento"})
+			return
+		}
+
+		chunks = append(chunks, Chunk{
+			ID:           newChunkID(document.ID, i),
+			DocumentID:   document.ID,
+			DocumentName: document.Name,
+			Text:         chunkTextValue,
+			Embedding:    embedding,
+		})
+	}
+
+	if err := vectorStore.Add(chunks); err != nil {
+		log.Printf("Erro ao indexar documento %s: %v", document.Name, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar documento"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":     document.ID,
+		"name":   document.Name,
+		"chunks": len(chunks),
+	})
+}
+
+// extractText normaliza o conteúdo enviado para texto simples. Markdown e
+// outros formatos de texto plano são usados como estão; PDFs passam pelo
+// parser em extractPDFText, que não lida com PDFs escaneados (sem texto
+// embutido, apenas imagem).
+func extractText(filename string, raw []byte) (string, error) {
+	if strings.HasSuffix(strings.ToLower(filename), ".pdf") {
+		return extractPDFText(raw)
+	}
+	return string(raw), nil
+}
+
+// extractPDFText lê o texto embutido de um PDF com a lib ledongthuc/pdf.
+// PDFs escaneados (só imagem, sem camada de texto) não têm o que extrair e
+// retornam erro, para não indexar lixo binário no vector store.
+func extractPDFText(raw []byte) (string, error) {
+	reader, err := pdf.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return "", fmt.Errorf("PDF inválido ou corrompido: %w", err)
+	}
+
+	text, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("erro ao extrair texto do PDF: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(text); err != nil {
+		return "", fmt.Errorf("erro ao ler texto extraído do PDF: %w", err)
+	}
+
+	if strings.TrimSpace(buf.String()) == "" {
+		return "", fmt.Errorf("PDF sem texto extraível (provavelmente escaneado)")
+	}
+
+	return buf.String(), nil
+}
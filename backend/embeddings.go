@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+)
+
+// embedRequest é o corpo aceito por embedContent.
+type embedRequest struct {
+	Content Content `json:"content"`
+}
+
+type embedResponse struct {
+	Embedding struct {
+		Values []float32 `json:"values"`
+	} `json:"embedding"`
+}
+
+// embedText chama o modelo de embeddings do Gemini e retorna o vetor
+// resultante, usado tanto na ingestão de documentos quanto para embutir a
+// pergunta do usuário na hora da busca.
+func embedText(ctx context.Context, text string) ([]float32, error) {
+	if geminiAPIKey == "" {
+		return nil, fmt.Errorf("RAG requer GEMINI_API_KEY configurada")
+	}
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/embedding-001:embedContent?key=%s", geminiAPIKey)
+
+	jsonData, err := json.Marshal(embedRequest{
+		Content: Content{Parts: []Part{{Text: text}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("erro ao gerar embedding: %s - %s", resp.Status, string(body))
+	}
+
+	var parsed embedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed.Embedding.Values, nil
+}
+
+// cosineSimilarity mede o quão próximos dois vetores de embedding são,
+// usado pelo memoryVectorStore para ordenar os candidatos mais relevantes.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
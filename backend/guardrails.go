@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// GuardrailRule descreve um padrão a ser detectado no texto (pergunta ou
+// resposta), carregado do YAML de configuração.
+type GuardrailRule struct {
+	Name     string `yaml:"name"`
+	Category string `yaml:"category"`
+	Pattern  string `yaml:"pattern"`
+	Reason   string `yaml:"reason"`
+
+	compiled *regexp.Regexp
+}
+
+// GuardrailConfig é a raiz do YAML de configuração das guardrails.
+type GuardrailConfig struct {
+	PreFilters  []GuardrailRule `yaml:"pre_filters"`
+	PostFilters []GuardrailRule `yaml:"post_filters"`
+}
+
+// guardrailViolation é retornado quando uma regra pré ou pós-filtro bate com
+// o texto analisado.
+type guardrailViolation struct {
+	Reason   string
+	Category string
+}
+
+// guardrailPipeline mantém o ruleset carregado do YAML, protegido para permitir
+// reload a quente via POST /api/admin/guardrails/reload.
+type guardrailPipeline struct {
+	mu     sync.RWMutex
+	config GuardrailConfig
+}
+
+var guardrailsPipeline = &guardrailPipeline{}
+
+// guardrailsConfigPath é o caminho do YAML com o ruleset, configurável para
+// facilitar testes e deployments com arquivos de configuração montados.
+var guardrailsConfigPath = "guardrails.yaml"
+
+func initGuardrails() {
+	if path := os.Getenv("GUARDRAILS_CONFIG"); path != "" {
+		guardrailsConfigPath = path
+	}
+
+	if err := guardrailsPipeline.reload(); err != nil {
+		log.Fatalf("Erro ao carregar guardrails de %s: %v", guardrailsConfigPath, err)
+	}
+}
+
+func (p *guardrailPipeline) reload() error {
+	raw, err := os.ReadFile(guardrailsConfigPath)
+	if err != nil {
+		return err
+	}
+
+	var config GuardrailConfig
+	if err := yaml.Unmarshal(raw, &config); err != nil {
+		return err
+	}
+
+	for i := range config.PreFilters {
+		if err := compileRule(&config.PreFilters[i]); err != nil {
+			return err
+		}
+	}
+	for i := range config.PostFilters {
+		if err := compileRule(&config.PostFilters[i]); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	p.config = config
+	p.mu.Unlock()
+
+	return nil
+}
+
+func compileRule(rule *GuardrailRule) error {
+	compiled, err := regexp.Compile("(?i)" + rule.Pattern)
+	if err != nil {
+		return err
+	}
+	rule.compiled = compiled
+	return nil
+}
+
+// checkPrompt roda os pre-filters sobre a pergunta do usuário, detectando
+// tentativas de jailbreak, inversão de papéis ou exfiltração do system
+// prompt antes de qualquer chamada ao backend de LLM.
+func (p *guardrailPipeline) checkPrompt(question string) *guardrailViolation {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return matchRules(p.config.PreFilters, question)
+}
+
+// checkAnswer roda os post-filters sobre a resposta do modelo, reaplicando o
+// mesmo tipo de verificação e, adicionalmente, checando se o conteúdo saiu
+// do tema educacional através do critic prompt em criticReview.
+func (p *guardrailPipeline) checkAnswer(ctx context.Context, backend Backend, question, answer string) *guardrailViolation {
+	p.mu.RLock()
+	rules := p.config.PostFilters
+	p.mu.RUnlock()
+
+	if violation := matchRules(rules, answer); violation != nil {
+		return violation
+	}
+
+	return criticReview(ctx, backend, question, answer)
+}
+
+func matchRules(rules []GuardrailRule, text string) *guardrailViolation {
+	for _, rule := range rules {
+		if rule.compiled != nil && rule.compiled.MatchString(text) {
+			return &guardrailViolation{Reason: rule.Reason, Category: rule.Category}
+		}
+	}
+	return nil
+}
+
+// criticPromptTemplate pede ao próprio backend para avaliar, em uma segunda
+// chamada, se a resposta permaneceu no escopo educacional de Cloud/DevOps.
+const criticPromptTemplate = `Você é um crítico que avalia se uma resposta de um assistente educacional de Cloud Computing, DevOps e AWS ficou dentro do tema.
+
+Pergunta original: %s
+
+Resposta a avaliar: %s
+
+Responda apenas com "OK" se a resposta for educacional e pertinente, ou com "FORA_DO_TEMA: <motivo>" caso contrário.`
+
+func criticReview(ctx context.Context, backend Backend, question, answer string) *guardrailViolation {
+	prompt := fmt.Sprintf(criticPromptTemplate, question, answer)
+
+	result, err := backend.Generate(ctx, prompt, BackendOptions{})
+	if err != nil {
+		// Fail closed: se o critic não roda, não há como confirmar que a
+		// resposta ficou no tema, então ela é tratada como uma violação em
+		// vez de passar sem verificação.
+		log.Printf("Erro ao rodar critic prompt das guardrails: %v", err)
+		return &guardrailViolation{Reason: "não foi possível verificar a resposta (falha no critic)", Category: "critic_indisponivel"}
+	}
+
+	verdict := strings.TrimSpace(result.Text)
+	if strings.HasPrefix(verdict, "FORA_DO_TEMA") {
+		return &guardrailViolation{Reason: verdict, Category: "fora_do_tema"}
+	}
+
+	return nil
+}
+
+// reloadGuardrails atende POST /api/admin/guardrails/reload: relê o YAML do
+// ruleset sem reiniciar o processo.
+func reloadGuardrails(c *gin.Context) {
+	if err := guardrailsPipeline.reload(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "erro ao recarregar guardrails: " + err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,10 +1,7 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -16,13 +13,33 @@ import (
 type QuestionRequest struct {
 	Question string `json:"question" binding:"required"`
 	Topic    string `json:"topic"`
+	Backend  string `json:"backend"`
+	UseRAG   bool   `json:"use_rag"`
 }
 
 type GeminiRequest struct {
-	Contents []Content `json:"contents"`
+	Contents       []Content       `json:"contents"`
+	SafetySettings []SafetySetting `json:"safetySettings,omitempty"`
+}
+
+// SafetySetting ajusta o limiar de bloqueio de uma categoria de conteúdo do
+// Gemini (ver safetySettings na API do Gemini).
+type SafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// defaultSafetySettings bloqueia conteúdo de risco médio ou maior nas
+// categorias padrão do Gemini, usado em toda chamada ao backend gemini.
+var defaultSafetySettings = []SafetySetting{
+	{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_MEDIUM_AND_ABOVE"},
+	{Category: "HARM_CATEGORY_HATE_SPEECH", Threshold: "BLOCK_MEDIUM_AND_ABOVE"},
+	{Category: "HARM_CATEGORY_SEXUALLY_EXPLICIT", Threshold: "BLOCK_MEDIUM_AND_ABOVE"},
+	{Category: "HARM_CATEGORY_DANGEROUS_CONTENT", Threshold: "BLOCK_MEDIUM_AND_ABOVE"},
 }
 
 type Content struct {
+	Role  string `json:"role,omitempty"`
 	Parts []Part `json:"parts"`
 }
 
@@ -38,12 +55,17 @@ type GeminiResponse struct {
 			} `json:"parts"`
 		} `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
 }
 
 type AnswerResponse struct {
 	Answer    string    `json:"answer"`
 	Topic     string    `json:"topic"`
 	Timestamp time.Time `json:"timestamp"`
+	Sources   []string  `json:"sources,omitempty"`
 }
 
 var geminiAPIKey string
@@ -51,15 +73,31 @@ var geminiAPIKey string
 func main() {
 	// Carregar configurações
 	geminiAPIKey = os.Getenv("GEMINI_API_KEY")
-	if geminiAPIKey == "" {
-		log.Fatal("GEMINI_API_KEY não configurada")
-	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	// Configurar backends de LLM disponíveis (Gemini, OpenAI-compatible, Ollama)
+	initBackends()
+	if len(backends) == 0 {
+		log.Fatal("nenhum backend de LLM configurado (GEMINI_API_KEY, OPENAI_API_KEY ou OLLAMA_URL)")
+	}
+
+	// Configurar armazenamento de sessões de conversa
+	initSessionStore()
+
+	// Configurar armazenamento vetorial para RAG sobre material de estudo
+	initVectorStore()
+
+	// Configurar autenticação por chave de API, rate limit e accounting de uso
+	initAuth()
+	initUsageRecorder()
+
+	// Carregar o ruleset de guardrails de prompt-injection e segurança
+	initGuardrails()
+
 	// Configurar Gin
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
@@ -76,8 +114,34 @@ func main() {
 	api := r.Group("/api")
 	{
 		api.GET("/health", healthCheck)
-		api.POST("/ask", askQuestion)
 		api.GET("/topics", getTopics)
+		api.GET("/backends", getBackends)
+
+		// Rotas que consomem um backend de LLM exigem uma app-key autenticada e
+		// estão sujeitas a rate limit e accounting de uso por chave.
+		authenticated := api.Group("")
+		authenticated.Use(authMiddleware(), rateLimitMiddleware())
+		{
+			authenticated.POST("/ask", askQuestion)
+			authenticated.POST("/ask/stream", askQuestionStream)
+			authenticated.POST("/documents", uploadDocument)
+			authenticated.GET("/usage", getUsage)
+
+			sessions := authenticated.Group("/sessions")
+			{
+				sessions.POST("", createSession)
+				sessions.GET("/:id", getSession)
+				sessions.DELETE("/:id", deleteSession)
+				sessions.POST("/:id/ask", askInSession)
+			}
+		}
+
+		admin := api.Group("/admin")
+		admin.Use(adminAuthMiddleware())
+		{
+			admin.GET("/usage", getAdminUsage)
+			admin.POST("/guardrails/reload", reloadGuardrails)
+		}
 	}
 
 	// Health check na raiz também (para ECS)
@@ -140,26 +204,69 @@ func askQuestion(c *gin.Context) {
 		return
 	}
 
-	// Criar prompt para o Gemini
-	prompt := createPrompt(req.Question, req.Topic)
+	backend, err := resolveBackend(req.Backend)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if violation := guardrailsPipeline.checkPrompt(req.Question); violation != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "pergunta bloqueada pelas guardrails", "reason": violation.Reason, "category": violation.Category})
+		return
+	}
+
+	var prompt string
+	var sources []string
+
+	if req.UseRAG {
+		chunks, err := retrieveChunks(c.Request.Context(), req.Question)
+		if err != nil {
+			log.Printf("Erro ao buscar contexto para RAG: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+			return
+		}
+
+		prompt = createRAGPrompt(req.Question, req.Topic, chunks)
+		for _, chunk := range chunks {
+			sources = append(sources, chunk.DocumentID)
+		}
+	} else {
+		prompt = createPrompt(req.Question, req.Topic)
+	}
 
-	// Chamar API do Gemini
-	answer, err := callGemini(prompt)
+	// Chamar o backend selecionado
+	result, err := backend.Generate(c.Request.Context(), prompt, BackendOptions{})
 	if err != nil {
-		log.Printf("Erro ao chamar Gemini: %v", err)
+		log.Printf("Erro ao chamar backend %s: %v", backend.Name(), err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
 		return
 	}
 
+	if violation := guardrailsPipeline.checkAnswer(c.Request.Context(), backend, req.Question, result.Text); violation != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "resposta bloqueada pelas guardrails", "reason": violation.Reason, "category": violation.Category})
+		return
+	}
+
+	recordUsage(c, backend.Name(), result.PromptTokens, result.CompletionTokens)
+
 	response := AnswerResponse{
-		Answer:    answer,
+		Answer:    result.Text,
 		Topic:     req.Topic,
 		Timestamp: time.Now(),
+		Sources:   sources,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+func getBackends(c *gin.Context) {
+	available, current := listBackends()
+	c.JSON(http.StatusOK, gin.H{
+		"backends": available,
+		"current":  current,
+	})
+}
+
 func createPrompt(question, topic string) string {
 	basePrompt := `Você é um assistente educacional especializado em Cloud Computing, DevOps e AWS.
 Responda de forma clara, didática e prática.`
@@ -173,44 +280,3 @@ Responda de forma clara, didática e prática.`
 
 	return basePrompt
 }
-
-func callGemini(prompt string) (string, error) {
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent?key=%s", geminiAPIKey)
-
-	requestBody := GeminiRequest{
-		Contents: []Content{
-			{
-				Parts: []Part{
-					{Text: prompt},
-				},
-			},
-		},
-	}
-
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", err
-	}
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("erro na API: %s - %s", resp.Status, string(body))
-	}
-
-	var geminiResp GeminiResponse
-	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
-		return "", err
-	}
-
-	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("resposta vazia do Gemini")
-	}
-
-	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
-}
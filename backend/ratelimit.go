@@ -0,0 +1,143 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenBucket limita tanto o número de requisições quanto uma quantidade
+// numérica arbitrária (tokens de prompt/completion) por minuto, recarregando
+// gradualmente conforme o tempo passa.
+type tokenBucket struct {
+	mu sync.Mutex
+
+	requestCapacity float64
+	requestTokens   float64
+	requestRate     float64 // tokens (requisições) recarregados por segundo
+
+	tokenCapacity float64
+	tokens        float64
+	tokenRate     float64 // tokens (de LLM) recarregados por segundo
+
+	lastRefill time.Time
+}
+
+func newTokenBucket(requestsPerMinute, tokensPerMinute int) *tokenBucket {
+	now := time.Now()
+	return &tokenBucket{
+		requestCapacity: float64(requestsPerMinute),
+		requestTokens:   float64(requestsPerMinute),
+		requestRate:     float64(requestsPerMinute) / 60,
+		tokenCapacity:   float64(tokensPerMinute),
+		tokens:          float64(tokensPerMinute),
+		tokenRate:       float64(tokensPerMinute) / 60,
+		lastRefill:      now,
+	}
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.lastRefill = now
+
+	b.requestTokens = min(b.requestCapacity, b.requestTokens+elapsed*b.requestRate)
+	b.tokens = min(b.tokenCapacity, b.tokens+elapsed*b.tokenRate)
+}
+
+// allowRequest consome uma unidade de requisição do bucket, retornando false
+// se o limite de requests/minuto já foi atingido.
+func (b *tokenBucket) allowRequest() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.requestTokens < 1 {
+		return false
+	}
+	b.requestTokens--
+	return true
+}
+
+// reserveEstimatedTokens verifica se o bucket ainda comporta a estimativa de
+// tokens da próxima chamada, sem debitar nada — o débito real acontece em
+// recordUsage depois que o tamanho real da resposta é conhecido.
+func (b *tokenBucket) reserveEstimatedTokens(estimated int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return b.tokens >= float64(estimated)
+}
+
+// debitTokens desconta do bucket de tokens/minuto a quantidade efetivamente
+// usada, podendo deixar o saldo negativo quando a estimativa ficou aquém do
+// uso real.
+func (b *tokenBucket) debitTokens(amount int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens -= float64(amount)
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// rateLimiter mantém um tokenBucket por chave de API.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+var limiter = &rateLimiter{buckets: make(map[string]*tokenBucket)}
+
+func (rl *rateLimiter) bucketFor(apiKey *APIKey) *tokenBucket {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	bucket, ok := rl.buckets[apiKey.Key]
+	if !ok {
+		bucket = newTokenBucket(apiKey.RequestsPerMinute, apiKey.TokensPerMinute)
+		rl.buckets[apiKey.Key] = bucket
+	}
+	return bucket
+}
+
+// estimatedRequestTokens estima o custo em tokens de uma chamada antes de
+// sabermos o tamanho real da resposta, usado só para not deixar a fila
+// explodir muito além do limite configurado.
+const estimatedRequestTokens = 500
+
+// rateLimitMiddleware aplica os limites de requests/minuto e tokens/minuto
+// da APIKey autenticada por authMiddleware. Deve ser registrado depois dele.
+func rateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := currentAPIKey(c)
+		if apiKey == nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "chave de API não autenticada"})
+			return
+		}
+
+		bucket := limiter.bucketFor(apiKey)
+
+		if !bucket.allowRequest() {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "limite de requisições por minuto excedido"})
+			return
+		}
+
+		if !bucket.reserveEstimatedTokens(estimatedRequestTokens) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "limite de tokens por minuto excedido"})
+			return
+		}
+
+		c.Set("rateBucket", bucket)
+		c.Next()
+	}
+}
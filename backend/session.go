@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// Turn representa uma pergunta e a resposta correspondente dentro de uma
+// sessão de conversa.
+type Turn struct {
+	Question  string    `json:"question"`
+	Answer    string    `json:"answer"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Session acumula o histórico de turnos de uma conversa, usado para montar o
+// array Contents com papéis alternados ao chamar o backend.
+type Session struct {
+	ID        string    `json:"id"`
+	Topic     string    `json:"topic"`
+	Turns     []Turn    `json:"turns"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version é um contador monotônico usado pelo dynamoSessionStore para
+	// optimistic locking em AppendTurn; updated_at não serve para isso
+	// porque duas escritas no mesmo segundo têm o mesmo valor truncado.
+	Version int64 `json:"-"`
+}
+
+// SessionStore abstrai onde as sessões são persistidas, permitindo trocar o
+// armazenamento em memória por um backend distribuído (ex.: DynamoDB) sem
+// alterar os handlers HTTP.
+type SessionStore interface {
+	Create(topic string) (*Session, error)
+	Get(id string) (*Session, error)
+	AppendTurn(id string, turn Turn) (*Session, error)
+	Delete(id string) error
+	// Evict remove sessões mais antigas que olderThan, usado pela goroutine de TTL.
+	Evict(olderThan time.Duration) (int, error)
+}
+
+var sessionStore SessionStore
+
+// sessionTTL define por quanto tempo uma sessão inativa é mantida antes de
+// ser removida pela goroutine de eviction.
+const sessionTTL = 30 * time.Minute
+
+// initSessionStore escolhe a implementação de SessionStore e inicia a
+// goroutine periódica de expiração de sessões antigas.
+func initSessionStore() {
+	if table := os.Getenv("SESSIONS_DYNAMODB_TABLE"); table != "" {
+		store, err := newDynamoSessionStore(context.Background(), table)
+		if err != nil {
+			log.Printf("Erro ao inicializar SessionStore do DynamoDB, usando memória: %v", err)
+		} else {
+			sessionStore = store
+			go evictExpiredSessions()
+			return
+		}
+	}
+
+	sessionStore = newMemorySessionStore()
+	go evictExpiredSessions()
+}
+
+func evictExpiredSessions() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		removed, err := sessionStore.Evict(sessionTTL)
+		if err != nil {
+			log.Printf("Erro ao expirar sessões: %v", err)
+			continue
+		}
+		if removed > 0 {
+			log.Printf("Sessões expiradas removidas: %d", removed)
+		}
+	}
+}
+
+// buildContents monta o array de Contents do Gemini alternando os papéis
+// user/model a partir do histórico da sessão, seguido da pergunta atual. O
+// primeiro turno carrega o prompt base (igual ao de createPrompt) para que a
+// sessão mantenha o mesmo tom educacional das perguntas avulsas.
+func buildContents(session *Session, question string) []Content {
+	contents := make([]Content, 0, len(session.Turns)*2+1)
+
+	for i, turn := range session.Turns {
+		userText := turn.Question
+		if i == 0 {
+			userText = createPrompt(turn.Question, session.Topic)
+		}
+		contents = append(contents,
+			Content{Role: "user", Parts: []Part{{Text: userText}}},
+			Content{Role: "model", Parts: []Part{{Text: turn.Answer}}},
+		)
+	}
+
+	nextQuestion := question
+	if len(session.Turns) == 0 {
+		nextQuestion = createPrompt(question, session.Topic)
+	}
+	contents = append(contents, Content{Role: "user", Parts: []Part{{Text: nextQuestion}}})
+
+	return contents
+}
+
+// flattenContents reduz o histórico de Contents a um único prompt de texto,
+// usado quando a sessão não está usando o backend gemini (que é o único a
+// aceitar Contents com múltiplos turnos).
+func flattenContents(contents []Content) string {
+	var flat string
+	for _, content := range contents {
+		for _, part := range content.Parts {
+			if flat != "" {
+				flat += "\n\n"
+			}
+			if content.Role == "model" {
+				flat += "Resposta anterior: " + part.Text
+			} else {
+				flat += part.Text
+			}
+		}
+	}
+	return flat
+}
+
+func newID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("sess-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// errSessionNotFound é retornado pelos SessionStore quando o ID não existe.
+var errSessionNotFound = fmt.Errorf("sessão não encontrada")
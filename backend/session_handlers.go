@@ -0,0 +1,116 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSessionRequest é o corpo aceito por POST /api/sessions.
+type CreateSessionRequest struct {
+	Topic string `json:"topic"`
+}
+
+// SessionAskRequest é o corpo aceito por POST /api/sessions/:id/ask.
+type SessionAskRequest struct {
+	Question string `json:"question" binding:"required"`
+	Backend  string `json:"backend"`
+}
+
+func createSession(c *gin.Context) {
+	var req CreateSessionRequest
+	// O corpo é opcional: uma sessão pode ser criada sem tópico definido.
+	_ = c.ShouldBindJSON(&req)
+
+	session, err := sessionStore.Create(req.Topic)
+	if err != nil {
+		log.Printf("Erro ao criar sessão: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar sessão"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func getSession(c *gin.Context) {
+	session, err := sessionStore.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sessão não encontrada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func deleteSession(c *gin.Context) {
+	if err := sessionStore.Delete(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sessão não encontrada"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func askInSession(c *gin.Context) {
+	sessionID := c.Param("id")
+
+	var req SessionAskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pergunta inválida"})
+		return
+	}
+
+	session, err := sessionStore.Get(sessionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sessão não encontrada"})
+		return
+	}
+
+	backend, err := resolveBackend(req.Backend)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if violation := guardrailsPipeline.checkPrompt(req.Question); violation != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "pergunta bloqueada pelas guardrails", "reason": violation.Reason, "category": violation.Category})
+		return
+	}
+
+	contents := buildContents(session, req.Question)
+
+	var result GenerateResult
+	if gemini, ok := backend.(*geminiBackend); ok {
+		result, err = gemini.GenerateWithContents(c.Request.Context(), contents, BackendOptions{})
+	} else {
+		result, err = backend.Generate(c.Request.Context(), flattenContents(contents), BackendOptions{})
+	}
+	if err != nil {
+		log.Printf("Erro ao chamar backend %s: %v", backend.Name(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+		return
+	}
+
+	if violation := guardrailsPipeline.checkAnswer(c.Request.Context(), backend, req.Question, result.Text); violation != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "resposta bloqueada pelas guardrails", "reason": violation.Reason, "category": violation.Category})
+		return
+	}
+
+	recordUsage(c, backend.Name(), result.PromptTokens, result.CompletionTokens)
+
+	turn := Turn{Question: req.Question, Answer: result.Text, Timestamp: time.Now()}
+	session, err = sessionStore.AppendTurn(sessionID, turn)
+	if err != nil {
+		log.Printf("Erro ao gravar turno da sessão: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AnswerResponse{
+		Answer:    result.Text,
+		Topic:     session.Topic,
+		Timestamp: turn.Timestamp,
+	})
+}
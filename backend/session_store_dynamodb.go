@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxAppendTurnRetries limita as tentativas de escrita otimista de
+// AppendTurn antes de desistir por contenção excessiva na mesma sessão.
+const maxAppendTurnRetries = 5
+
+// dynamoSessionStore persiste sessões no DynamoDB, permitindo rodar várias
+// instâncias do backend atrás de um load balancer no ECS sem perder o
+// histórico de conversa entre requisições atendidas por instâncias diferentes.
+type dynamoSessionStore struct {
+	client *dynamodb.Client
+	table  string
+}
+
+// dynamoSessionItem é a representação da sessão salva no DynamoDB; os turnos
+// são serializados como JSON para evitar modelar uma lista aninhada de mapas.
+type dynamoSessionItem struct {
+	ID        string `dynamodbav:"id"`
+	Topic     string `dynamodbav:"topic"`
+	TurnsJSON string `dynamodbav:"turns_json"`
+	CreatedAt int64  `dynamodbav:"created_at"`
+	UpdatedAt int64  `dynamodbav:"updated_at"`
+	Version   int64  `dynamodbav:"version"`
+	ExpiresAt int64  `dynamodbav:"updated_at_ttl"`
+}
+
+func newDynamoSessionStore(ctx context.Context, table string) (*dynamoSessionStore, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar configuração da AWS: %w", err)
+	}
+
+	return &dynamoSessionStore{
+		client: dynamodb.NewFromConfig(cfg),
+		table:  table,
+	}, nil
+}
+
+func (s *dynamoSessionStore) Create(topic string) (*Session, error) {
+	now := time.Now()
+	session := &Session{
+		ID:        newID(),
+		Topic:     topic,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+
+	if err := s.put(session, nil); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *dynamoSessionStore) Get(id string) (*Session, error) {
+	out, err := s.client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName: aws.String(s.table),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar sessão no DynamoDB: %w", err)
+	}
+	if out.Item == nil {
+		return nil, errSessionNotFound
+	}
+
+	return itemToSession(out.Item)
+}
+
+// AppendTurn usa optimistic locking sobre um contador Version: duas chamadas
+// concorrentes na mesma sessão (ex.: double-submit, duas abas) não podem
+// simplesmente ler-modificar-escrever, ou a escrita que terminar por último
+// sobrescreve silenciosamente a outra. updated_at não serve de lock porque
+// tem resolução de segundos e duas escritas no mesmo segundo teriam o mesmo
+// valor; Version é incrementado a cada escrita bem-sucedida. Em caso de
+// conflito, relê a sessão e tenta de novo.
+func (s *dynamoSessionStore) AppendTurn(id string, turn Turn) (*Session, error) {
+	for attempt := 0; attempt < maxAppendTurnRetries; attempt++ {
+		session, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+
+		expectedVersion := session.Version
+		session.Turns = append(session.Turns, turn)
+		session.UpdatedAt = time.Now()
+		session.Version = expectedVersion + 1
+
+		err = s.put(session, &expectedVersion)
+		if err == nil {
+			return session, nil
+		}
+
+		var conflict *types.ConditionalCheckFailedException
+		if !errors.As(err, &conflict) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("erro ao gravar turno da sessão %s: muita concorrência, desistindo após %d tentativas", id, maxAppendTurnRetries)
+}
+
+func (s *dynamoSessionStore) Delete(id string) error {
+	_, err := s.client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.table),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao remover sessão no DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// Evict não varre a tabela: a expiração fica a cargo do TTL nativo do
+// DynamoDB, configurado no atributo updated_at_ttl da tabela.
+func (s *dynamoSessionStore) Evict(olderThan time.Duration) (int, error) {
+	return 0, nil
+}
+
+// put grava a sessão inteira. Quando expectedVersion não é nil, a escrita só
+// é aceita se o version atual no DynamoDB ainda bater com o valor lido antes
+// da modificação (optimistic locking); caso contrário a chamada falha com
+// *types.ConditionalCheckFailedException.
+func (s *dynamoSessionStore) put(session *Session, expectedVersion *int64) error {
+	turnsJSON, err := json.Marshal(session.Turns)
+	if err != nil {
+		return err
+	}
+
+	item, err := attributevalue.MarshalMap(dynamoSessionItem{
+		ID:        session.ID,
+		Topic:     session.Topic,
+		TurnsJSON: string(turnsJSON),
+		CreatedAt: session.CreatedAt.Unix(),
+		UpdatedAt: session.UpdatedAt.Unix(),
+		Version:   session.Version,
+		ExpiresAt: session.UpdatedAt.Add(sessionTTL).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(s.table),
+		Item:      item,
+	}
+
+	if expectedVersion != nil {
+		input.ConditionExpression = aws.String("version = :expected_version")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":expected_version": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", *expectedVersion)},
+		}
+	}
+
+	_, err = s.client.PutItem(context.Background(), input)
+	if err != nil {
+		var conflict *types.ConditionalCheckFailedException
+		if errors.As(err, &conflict) {
+			return conflict
+		}
+		return fmt.Errorf("erro ao salvar sessão no DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func itemToSession(raw map[string]types.AttributeValue) (*Session, error) {
+	var item dynamoSessionItem
+	if err := attributevalue.UnmarshalMap(raw, &item); err != nil {
+		return nil, err
+	}
+
+	var turns []Turn
+	if err := json.Unmarshal([]byte(item.TurnsJSON), &turns); err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		ID:        item.ID,
+		Topic:     item.Topic,
+		Turns:     turns,
+		CreatedAt: time.Unix(item.CreatedAt, 0),
+		UpdatedAt: time.Unix(item.UpdatedAt, 0),
+		Version:   item.Version,
+	}, nil
+}
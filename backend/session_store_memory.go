@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// memorySessionStore é a implementação padrão de SessionStore, adequada para
+// uma única instância do servidor. Para múltiplas instâncias atrás de um
+// load balancer (ex.: no ECS), use dynamoSessionStore.
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]*Session)}
+}
+
+func (s *memorySessionStore) Create(topic string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	session := &Session{
+		ID:        newID(),
+		Topic:     topic,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.sessions[session.ID] = session
+
+	return session, nil
+}
+
+func (s *memorySessionStore) Get(id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, errSessionNotFound
+	}
+
+	return cloneSession(session), nil
+}
+
+func (s *memorySessionStore) AppendTurn(id string, turn Turn) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, errSessionNotFound
+	}
+
+	session.Turns = append(session.Turns, turn)
+	session.UpdatedAt = time.Now()
+
+	return cloneSession(session), nil
+}
+
+// cloneSession copia a sessão e seu slice de turnos antes de devolvê-la fora
+// do lock de memorySessionStore. Sem isso, o chamador recebe o mesmo
+// ponteiro guardado no map e pode serializá-lo (ex.: em getSession) enquanto
+// outra requisição o muta em AppendTurn, uma corrida real sobre o mesmo
+// *Session.
+func cloneSession(session *Session) *Session {
+	cloned := *session
+	cloned.Turns = append([]Turn(nil), session.Turns...)
+	return &cloned
+}
+
+func (s *memorySessionStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[id]; !ok {
+		return errSessionNotFound
+	}
+	delete(s.sessions, id)
+
+	return nil
+}
+
+func (s *memorySessionStore) Evict(olderThan time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	for id, session := range s.sessions {
+		if session.UpdatedAt.Before(cutoff) {
+			delete(s.sessions, id)
+			removed++
+		}
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamChunk reflete o JSON de cada evento `data:` retornado por
+// streamGenerateContent com alt=sse — a mesma forma de GeminiResponse, mas
+// chegando aos poucos conforme o modelo gera texto.
+type streamChunk struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+}
+
+// askQuestionStream atende POST /api/ask/stream: chama o
+// streamGenerateContent do Gemini via Server-Sent Events. O post-filter de
+// guardrails (checkAnswer) só pode avaliar a resposta inteira, então os
+// deltas do Gemini são acumulados e só repassados ao cliente depois que a
+// resposta completa passa pelas guardrails — isto troca a renderização
+// token-a-token verdadeira por uma entrega em lote pós-verificação, mas é o
+// único jeito de as guardrails de saída realmente bloquearem algo aqui.
+func askQuestionStream(c *gin.Context) {
+	var req QuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pergunta inválida"})
+		return
+	}
+
+	if geminiAPIKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "streaming disponível apenas para o backend gemini"})
+		return
+	}
+
+	if violation := guardrailsPipeline.checkPrompt(req.Question); violation != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "pergunta bloqueada pelas guardrails", "reason": violation.Reason, "category": violation.Category})
+		return
+	}
+
+	prompt := createPrompt(req.Question, req.Topic)
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:streamGenerateContent?alt=sse&key=%s", geminiAPIKey)
+
+	requestBody := GeminiRequest{
+		Contents: []Content{
+			{Parts: []Part{{Text: prompt}}},
+		},
+		SafetySettings: defaultSafetySettings,
+	}
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(jsonData)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+		return
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Erro ao chamar Gemini (stream): %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+		return
+	}
+
+	var full strings.Builder
+	var deltas []string
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &chunk); err != nil {
+			continue
+		}
+		if len(chunk.Candidates) == 0 || len(chunk.Candidates[0].Content.Parts) == 0 {
+			continue
+		}
+
+		delta := chunk.Candidates[0].Content.Parts[0].Text
+		full.WriteString(delta)
+		deltas = append(deltas, delta)
+	}
+
+	backend, err := resolveBackend("gemini")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar pergunta"})
+		return
+	}
+
+	if violation := guardrailsPipeline.checkAnswer(ctx, backend, req.Question, full.String()); violation != nil {
+		c.Stream(func(w io.Writer) bool {
+			payload, _ := json.Marshal(gin.H{"error": "resposta bloqueada pelas guardrails", "reason": violation.Reason, "category": violation.Category})
+			c.SSEvent("error", string(payload))
+			return false
+		})
+		return
+	}
+
+	recordUsage(c, "gemini", estimateTokens(prompt), estimateTokens(full.String()))
+
+	i := 0
+	c.Stream(func(w io.Writer) bool {
+		if i < len(deltas) {
+			payload, _ := json.Marshal(gin.H{"text": deltas[i]})
+			c.SSEvent("token", string(payload))
+			i++
+			return true
+		}
+
+		final := AnswerResponse{
+			Answer:    full.String(),
+			Topic:     req.Topic,
+			Timestamp: time.Now(),
+		}
+		payload, _ := json.Marshal(final)
+		c.SSEvent("done", string(payload))
+		return false
+	})
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageEvent registra o custo de uma chamada ao backend de LLM, atribuído à
+// chave de API que a originou.
+type UsageEvent struct {
+	APIKey           string    `json:"api_key"`
+	Backend          string    `json:"backend"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	EstimatedCostUSD float64   `json:"estimated_cost_usd"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// UsageSummary agrega os UsageEvent de uma chave (ou de todas, para o
+// endpoint de admin).
+type UsageSummary struct {
+	APIKey           string  `json:"api_key,omitempty"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// UsageRecorder abstrai onde os UsageEvent são guardados, permitindo trocar
+// o armazenamento em memória por um banco de séries temporais sem alterar os
+// handlers que chamam Record.
+type UsageRecorder interface {
+	Record(event UsageEvent)
+	SummaryFor(apiKey string) UsageSummary
+	SummaryAll() []UsageSummary
+}
+
+var usageRecorder UsageRecorder
+
+func initUsageRecorder() {
+	usageRecorder = newMemoryUsageRecorder()
+}
+
+// costPerThousandTokens é uma estimativa fixa de custo para fins de
+// relatório; não reflete a tabela de preços real de cada provedor.
+const costPerThousandTokens = 0.002
+
+func estimateCostUSD(promptTokens, completionTokens int) float64 {
+	return float64(promptTokens+completionTokens) / 1000 * costPerThousandTokens
+}
+
+// recordUsage grava o consumo da chamada atual para a APIKey autenticada e
+// desconta o bucket de tokens/minuto com o uso real (reservado de forma
+// aproximada por rateLimitMiddleware antes da chamada ao backend).
+func recordUsage(c *gin.Context, backendName string, promptTokens, completionTokens int) {
+	apiKey := currentAPIKey(c)
+	if apiKey == nil {
+		return
+	}
+
+	usageRecorder.Record(UsageEvent{
+		APIKey:           apiKey.Key,
+		Backend:          backendName,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		EstimatedCostUSD: estimateCostUSD(promptTokens, completionTokens),
+		Timestamp:        time.Now(),
+	})
+
+	if bucket, ok := c.Get("rateBucket"); ok {
+		bucket.(*tokenBucket).debitTokens(promptTokens + completionTokens)
+	}
+}
+
+// getUsage atende GET /api/usage: retorna o consumo agregado da chave de API
+// autenticada na requisição.
+func getUsage(c *gin.Context) {
+	apiKey := currentAPIKey(c)
+	if apiKey == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "chave de API não autenticada"})
+		return
+	}
+
+	c.JSON(http.StatusOK, usageRecorder.SummaryFor(apiKey.Key))
+}
+
+// getAdminUsage atende GET /api/admin/usage: retorna o consumo agregado de
+// todas as chaves, protegido por adminAuthMiddleware.
+func getAdminUsage(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"usage": usageRecorder.SummaryAll()})
+}
+
+type memoryUsageRecorder struct {
+	mu     sync.Mutex
+	events []UsageEvent
+}
+
+func newMemoryUsageRecorder() *memoryUsageRecorder {
+	return &memoryUsageRecorder{}
+}
+
+func (r *memoryUsageRecorder) Record(event UsageEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *memoryUsageRecorder) SummaryFor(apiKey string) UsageSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := UsageSummary{APIKey: apiKey}
+	for _, event := range r.events {
+		if event.APIKey != apiKey {
+			continue
+		}
+		summary.Requests++
+		summary.PromptTokens += event.PromptTokens
+		summary.CompletionTokens += event.CompletionTokens
+		summary.EstimatedCostUSD += event.EstimatedCostUSD
+	}
+
+	return summary
+}
+
+func (r *memoryUsageRecorder) SummaryAll() []UsageSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byKey := make(map[string]*UsageSummary)
+	var order []string
+
+	for _, event := range r.events {
+		summary, ok := byKey[event.APIKey]
+		if !ok {
+			summary = &UsageSummary{APIKey: event.APIKey}
+			byKey[event.APIKey] = summary
+			order = append(order, event.APIKey)
+		}
+		summary.Requests++
+		summary.PromptTokens += event.PromptTokens
+		summary.CompletionTokens += event.CompletionTokens
+		summary.EstimatedCostUSD += event.EstimatedCostUSD
+	}
+
+	summaries := make([]UsageSummary, 0, len(order))
+	for _, key := range order {
+		summaries = append(summaries, *byKey[key])
+	}
+
+	return summaries
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sort"
+	"sync"
+)
+
+// memoryVectorStore mantém todos os chunks embutidos em memória e faz a
+// busca por similaridade com uma varredura linear. Suficiente para o volume
+// de material de estudo de um único usuário; para uso multi-tenant em
+// produção, use pgvectorStore.
+type memoryVectorStore struct {
+	mu     sync.Mutex
+	chunks []Chunk
+}
+
+func newMemoryVectorStore() *memoryVectorStore {
+	return &memoryVectorStore{}
+}
+
+func (s *memoryVectorStore) Add(chunks []Chunk) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chunks = append(s.chunks, chunks...)
+	return nil
+}
+
+func (s *memoryVectorStore) Search(embedding []float32, k int) ([]Chunk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type scored struct {
+		chunk Chunk
+		score float64
+	}
+
+	scoredChunks := make([]scored, 0, len(s.chunks))
+	for _, chunk := range s.chunks {
+		scoredChunks = append(scoredChunks, scored{
+			chunk: chunk,
+			score: cosineSimilarity(embedding, chunk.Embedding),
+		})
+	}
+
+	sort.Slice(scoredChunks, func(i, j int) bool {
+		return scoredChunks[i].score > scoredChunks[j].score
+	})
+
+	if k > len(scoredChunks) {
+		k = len(scoredChunks)
+	}
+
+	top := make([]Chunk, k)
+	for i := 0; i < k; i++ {
+		top[i] = scoredChunks[i].chunk
+	}
+
+	return top, nil
+}
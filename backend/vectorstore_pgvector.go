@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+// pgvectorStore persiste os chunks e seus embeddings no Postgres usando a
+// extensão pgvector, permitindo busca por similaridade sem depender de
+// memória do processo — útil quando o backend roda com várias réplicas.
+type pgvectorStore struct {
+	db *sql.DB
+}
+
+func newPgvectorStore(db *sql.DB) *pgvectorStore {
+	return &pgvectorStore{db: db}
+}
+
+func (s *pgvectorStore) Add(chunks []Chunk) error {
+	for _, chunk := range chunks {
+		_, err := s.db.ExecContext(context.Background(),
+			`INSERT INTO document_chunks (id, document_id, document_name, text, embedding)
+			 VALUES ($1, $2, $3, $4, $5)`,
+			chunk.ID, chunk.DocumentID, chunk.DocumentName, chunk.Text, pgvector.NewVector(chunk.Embedding),
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir chunk no pgvector: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *pgvectorStore) Search(embedding []float32, k int) ([]Chunk, error) {
+	rows, err := s.db.QueryContext(context.Background(),
+		`SELECT id, document_id, document_name, text
+		 FROM document_chunks
+		 ORDER BY embedding <=> $1
+		 LIMIT $2`,
+		pgvector.NewVector(embedding), k,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar chunks no pgvector: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []Chunk
+	for rows.Next() {
+		var chunk Chunk
+		if err := rows.Scan(&chunk.ID, &chunk.DocumentID, &chunk.DocumentName, &chunk.Text); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, rows.Err()
+}